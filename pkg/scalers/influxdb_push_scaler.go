@@ -0,0 +1,492 @@
+package scalers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// influxDBPushScaler is a PushScaler: it exposes an HTTP endpoint compatible with the
+// InfluxDB 2.x /api/v2/write line-protocol API so Telegraf can push measurements into KEDA
+type influxDBPushScaler struct {
+	metadata *influxDBPushMetadata
+	store    *influxDBPushStore
+}
+
+type influxDBPushMetadata struct {
+	metricName          string
+	measurement         string
+	field               string
+	aggregation         string
+	thresholdValue      float64
+	activationThreshold float64
+	retentionWindow     time.Duration
+	listenAddress       string
+	path                string
+	organizationName    string
+	bucket              string
+	authToken           string
+	scalerIndex         int
+}
+
+var influxDBPushLog = logf.Log.WithName("influxdb_push_scaler")
+
+const (
+	influxDBPushDefaultListenAddress   = ":8080"
+	influxDBPushDefaultPath            = "/api/v2/write"
+	influxDBPushDefaultRetentionWindow = time.Minute
+	influxDBPushMaxBodyBytes           = 1 << 20
+	influxDBPushActiveCheckInterval    = time.Second
+)
+
+// NewInfluxDBPushScaler creates a new influxdb push scaler
+func NewInfluxDBPushScaler(config *ScalerConfig) (Scaler, error) {
+	meta, err := parseInfluxDBPushMetadata(config)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing influxdb push metadata: %s", err)
+	}
+
+	listener, err := acquireInfluxDBPushListener(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &influxDBPushScaler{metadata: meta, store: listener.store}, nil
+}
+
+// parseInfluxDBPushMetadata parses the metadata passed in from the ScaledObject config
+func parseInfluxDBPushMetadata(config *ScalerConfig) (*influxDBPushMetadata, error) {
+	var metricName string
+	var measurement string
+	var field string
+	var thresholdValue float64
+	var activationThreshold float64
+	var listenAddress string
+	var path string
+	var organizationName string
+	var bucket string
+	var authToken string
+
+	if val, ok := config.TriggerMetadata["measurement"]; ok {
+		measurement = val
+	} else {
+		return nil, fmt.Errorf("no measurement given")
+	}
+
+	if val, ok := config.TriggerMetadata["field"]; ok {
+		field = val
+	} else {
+		return nil, fmt.Errorf("no field given")
+	}
+
+	if val, ok := config.TriggerMetadata["thresholdValue"]; ok {
+		value, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("thresholdValue: failed to parse thresholdValue length %s", err.Error())
+		}
+		thresholdValue = value
+	} else {
+		return nil, fmt.Errorf("no threshold value given")
+	}
+
+	if val, ok := config.TriggerMetadata["activationThreshold"]; ok {
+		value, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationThreshold: failed to parse activationThreshold length %s", err.Error())
+		}
+		activationThreshold = value
+	}
+
+	aggregation, err := parseInfluxDBAggregation(config.TriggerMetadata["aggregation"])
+	if err != nil {
+		return nil, err
+	}
+
+	retentionWindow := influxDBPushDefaultRetentionWindow
+	if val, ok := config.TriggerMetadata["retentionWindow"]; ok {
+		parsed, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("retentionWindow: failed to parse retentionWindow %s", err.Error())
+		}
+		retentionWindow = parsed
+	}
+
+	listenAddress = influxDBPushDefaultListenAddress
+	if val, ok := config.TriggerMetadata["listenAddress"]; ok {
+		listenAddress = val
+	}
+
+	path = influxDBPushDefaultPath
+	if val, ok := config.TriggerMetadata["path"]; ok {
+		path = val
+	}
+
+	if val, ok := config.TriggerMetadata["organizationName"]; ok {
+		organizationName = val
+	}
+
+	if val, ok := config.TriggerMetadata["bucket"]; ok {
+		bucket = val
+	}
+
+	switch {
+	case config.TriggerMetadata["authToken"] != "":
+		authToken = config.TriggerMetadata["authToken"]
+	case config.TriggerMetadata["authTokenFromEnv"] != "":
+		if val, ok := config.ResolvedEnv[config.TriggerMetadata["authTokenFromEnv"]]; ok {
+			authToken = val
+		} else {
+			return nil, fmt.Errorf("no auth token given")
+		}
+	case config.AuthParams["authToken"] != "":
+		authToken = config.AuthParams["authToken"]
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok {
+		metricName = kedautil.NormalizeString(fmt.Sprintf("influxdb-push-%s", val))
+	} else {
+		metricName = kedautil.NormalizeString(fmt.Sprintf("influxdb-push-%s-%s", measurement, field))
+	}
+
+	return &influxDBPushMetadata{
+		metricName:          metricName,
+		measurement:         measurement,
+		field:               field,
+		aggregation:         aggregation,
+		thresholdValue:      thresholdValue,
+		activationThreshold: activationThreshold,
+		retentionWindow:     retentionWindow,
+		listenAddress:       listenAddress,
+		path:                path,
+		organizationName:    organizationName,
+		bucket:              bucket,
+		authToken:           authToken,
+		scalerIndex:         config.ScalerIndex,
+	}, nil
+}
+
+// IsActive returns true if the aggregated value over the retention window is above the activation threshold
+func (s *influxDBPushScaler) IsActive(context.Context) (bool, error) {
+	return s.value() > s.metadata.activationThreshold, nil
+}
+
+// Run implements PushScaler. The listener and store backing this scaler live in the
+// registry below and outlive any single rebuild, so Run only watches the shared store
+func (s *influxDBPushScaler) Run(ctx context.Context, active chan<- bool) {
+	defer close(active)
+
+	ticker := time.NewTicker(influxDBPushActiveCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			active <- s.value() > s.metadata.activationThreshold
+		}
+	}
+}
+
+// value returns the current aggregated scaling signal for the configured measurement/field
+func (s *influxDBPushScaler) value() float64 {
+	values := s.store.values(s.metadata.measurement, s.metadata.field)
+	if len(values) == 0 {
+		return 0
+	}
+	return aggregateInfluxDBValues(values, s.metadata.aggregation)
+}
+
+// Close releases this scaler's reference to its shared push listener
+func (s *influxDBPushScaler) Close(context.Context) error {
+	return releaseInfluxDBPushListener(s.metadata)
+}
+
+// GetMetrics returns the aggregated value pushed for the configured measurement/field
+func (s *influxDBPushScaler) GetMetrics(_ context.Context, metricName string, _ labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(int64(s.value()), resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the Horizontal Pod Autoscaler
+func (s *influxDBPushScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(int64(s.metadata.thresholdValue), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// influxDBPushListener is a shared HTTP listener, server and store for one listenAddress.
+// Scalers are rebuilt across reconciles and HPA polls, but the listener and the
+// measurements Telegraf has pushed must not be torn down every time that happens, so
+// every influxDBPushScaler built for the same listenAddress acquires a reference to the
+// same listener instead of binding its own port and starting with an empty store
+type influxDBPushListener struct {
+	listener     net.Listener
+	server       *http.Server
+	mux          *http.ServeMux
+	store        *influxDBPushStore
+	refCount     int
+	pathMetadata map[string]*influxDBPushMetadata
+}
+
+var (
+	influxDBPushListenersMu sync.Mutex
+	influxDBPushListeners   = map[string]*influxDBPushListener{}
+)
+
+// acquireInfluxDBPushListener returns the shared listener for meta.listenAddress,
+// binding it synchronously (surfacing any bind error to the caller) if this is the
+// first scaler to reference that address. Registers meta.path on it if needed, or
+// errors if meta.path is already serving a different authToken/organizationName/bucket
+func acquireInfluxDBPushListener(meta *influxDBPushMetadata) (*influxDBPushListener, error) {
+	influxDBPushListenersMu.Lock()
+	defer influxDBPushListenersMu.Unlock()
+
+	l, ok := influxDBPushListeners[meta.listenAddress]
+	if !ok {
+		ln, err := net.Listen("tcp", meta.listenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("error starting influxdb push listener on %s: %s", meta.listenAddress, err)
+		}
+
+		l = &influxDBPushListener{
+			listener:     ln,
+			mux:          http.NewServeMux(),
+			store:        newInfluxDBPushStore(meta.retentionWindow),
+			pathMetadata: map[string]*influxDBPushMetadata{},
+		}
+		l.server = &http.Server{Handler: l.mux}
+
+		influxDBPushLog.Info("starting up influxdb push listener", "address", meta.listenAddress)
+		go func() {
+			if err := l.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+				influxDBPushLog.Error(err, "influxdb push listener stopped unexpectedly")
+			}
+		}()
+
+		influxDBPushListeners[meta.listenAddress] = l
+	}
+
+	if existing, ok := l.pathMetadata[meta.path]; ok {
+		if existing.authToken != meta.authToken || existing.organizationName != meta.organizationName || existing.bucket != meta.bucket {
+			return nil, fmt.Errorf("influxdb push scaler: listenAddress %s path %s is already serving a different authToken/organizationName/bucket", meta.listenAddress, meta.path)
+		}
+	} else {
+		l.mux.HandleFunc(meta.path, newInfluxDBPushHandler(meta, l.store))
+		l.pathMetadata[meta.path] = meta
+	}
+
+	l.refCount++
+	return l, nil
+}
+
+// releaseInfluxDBPushListener drops this scaler's reference to its listener,
+// closing and forgetting it once the last referencing scaler has released it
+func releaseInfluxDBPushListener(meta *influxDBPushMetadata) error {
+	influxDBPushListenersMu.Lock()
+	defer influxDBPushListenersMu.Unlock()
+
+	l, ok := influxDBPushListeners[meta.listenAddress]
+	if !ok {
+		return nil
+	}
+
+	l.refCount--
+	if l.refCount > 0 {
+		return nil
+	}
+
+	delete(influxDBPushListeners, meta.listenAddress)
+	return l.server.Close()
+}
+
+// newInfluxDBPushHandler builds the HTTP handler implementing enough of the InfluxDB
+// 2.x /api/v2/write API for Telegraf's outputs.influxdb_v2 plugin to push into store
+func newInfluxDBPushHandler(meta *influxDBPushMetadata, store *influxDBPushStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if meta.authToken != "" && r.Header.Get("Authorization") != fmt.Sprintf("Token %s", meta.authToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if meta.organizationName != "" && r.URL.Query().Get("org") != meta.organizationName {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if meta.bucket != "" && r.URL.Query().Get("bucket") != meta.bucket {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		precision := r.URL.Query().Get("precision")
+		if precision == "" {
+			precision = "ns"
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, influxDBPushMaxBodyBytes)
+		body, err := io.ReadAll(r.Body)
+		defer r.Body.Close()
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			measurement, tagSetKey, fields, timestamp, err := parseLineProtocolLine(line, precision)
+			if err != nil {
+				influxDBPushLog.Error(err, "failed to parse line-protocol record, skipping")
+				continue
+			}
+			store.ingest(measurement, tagSetKey, fields, timestamp)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// parseLineProtocolLine parses one line-protocol record (`measurement[,tag=value...]
+// field=value[,field=value...] [timestamp]`) into its measurement, tag-set key and
+// numeric fields; it covers the common unescaped case, skipping non-numeric fields
+func parseLineProtocolLine(line string, precision string) (string, string, map[string]float64, time.Time, error) {
+	parts := strings.Fields(line)
+	if len(parts) < 2 {
+		return "", "", nil, time.Time{}, fmt.Errorf("malformed line-protocol record: %q", line)
+	}
+
+	measurementAndTags := strings.Split(parts[0], ",")
+	measurement := measurementAndTags[0]
+	tagSetKey := strings.Join(measurementAndTags[1:], ",")
+
+	fields := map[string]float64{}
+	for _, kv := range strings.Split(parts[1], ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		raw := strings.TrimSuffix(strings.TrimSuffix(pair[1], "i"), "u")
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		fields[pair[0]] = value
+	}
+
+	timestamp := time.Now()
+	if len(parts) > 2 {
+		rawTimestamp, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return "", "", nil, time.Time{}, fmt.Errorf("malformed line-protocol timestamp: %q", parts[2])
+		}
+		switch precision {
+		case "s":
+			timestamp = time.Unix(rawTimestamp, 0)
+		case "ms":
+			timestamp = time.UnixMilli(rawTimestamp)
+		case "us":
+			timestamp = time.UnixMicro(rawTimestamp)
+		default:
+			timestamp = time.Unix(0, rawTimestamp)
+		}
+	}
+
+	return measurement, tagSetKey, fields, timestamp, nil
+}
+
+// influxDBPushRecord is the most recent set of fields pushed for a (measurement, tag-set) pair
+type influxDBPushRecord struct {
+	fields    map[string]float64
+	timestamp time.Time
+}
+
+// influxDBPushStore keeps the latest record per (measurement, tag-set), pruning anything older than retentionWindow on read
+type influxDBPushStore struct {
+	mu              sync.Mutex
+	retentionWindow time.Duration
+	series          map[string]map[string]*influxDBPushRecord
+}
+
+func newInfluxDBPushStore(retentionWindow time.Duration) *influxDBPushStore {
+	return &influxDBPushStore{
+		retentionWindow: retentionWindow,
+		series:          map[string]map[string]*influxDBPushRecord{},
+	}
+}
+
+// ingest records the latest fields pushed for a (measurement, tag-set) pair
+func (s *influxDBPushStore) ingest(measurement, tagSetKey string, fields map[string]float64, timestamp time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.series[measurement] == nil {
+		s.series[measurement] = map[string]*influxDBPushRecord{}
+	}
+	s.series[measurement][tagSetKey] = &influxDBPushRecord{fields: fields, timestamp: timestamp}
+}
+
+// values returns field's value from every tag-set of measurement still within the retention window, pruning the rest;
+// values are ordered oldest first, so the "last" aggregation picks the most recent record
+func (s *influxDBPushStore) values(measurement string, field string) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-s.retentionWindow)
+	var records []*influxDBPushRecord
+	for tagSetKey, record := range s.series[measurement] {
+		if record.timestamp.Before(cutoff) {
+			delete(s.series[measurement], tagSetKey)
+			continue
+		}
+		if _, ok := record.fields[field]; ok {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].timestamp.Before(records[j].timestamp) })
+
+	values := make([]float64, 0, len(records))
+	for _, record := range records {
+		values = append(values, record.fields[field])
+	}
+	return values
+}
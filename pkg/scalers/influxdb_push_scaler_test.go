@@ -0,0 +1,104 @@
+package scalers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLineProtocolLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		precision string
+		wantMeas  string
+		wantTags  string
+		wantField float64
+		wantErr   bool
+	}{
+		{"simple", "cpu value=1.5 1000000000", "ns", "cpu", "", 1.5, false},
+		{"with tags", "cpu,host=a value=2 1000000000", "ns", "cpu", "host=a", 2, false},
+		{"int suffix", "cpu value=3i 1000000000", "ns", "cpu", "", 3, false},
+		{"seconds precision", "cpu value=1 1", "s", "cpu", "", 1, false},
+		{"no fields", "cpu", "ns", "", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			measurement, tagSetKey, fields, _, err := parseLineProtocolLine(tt.line, tt.precision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if measurement != tt.wantMeas {
+				t.Errorf("measurement = %q, want %q", measurement, tt.wantMeas)
+			}
+			if tagSetKey != tt.wantTags {
+				t.Errorf("tagSetKey = %q, want %q", tagSetKey, tt.wantTags)
+			}
+			if fields["value"] != tt.wantField {
+				t.Errorf("fields[value] = %v, want %v", fields["value"], tt.wantField)
+			}
+		})
+	}
+}
+
+func TestParseLineProtocolLineSkipsNonNumericFields(t *testing.T) {
+	_, _, fields, _, err := parseLineProtocolLine(`cpu value=1,label="x" 1000000000`, "ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(fields) != 1 || fields["value"] != 1 {
+		t.Errorf("expected only the numeric field to be kept, got %v", fields)
+	}
+}
+
+func TestInfluxDBPushStoreValuesPrunesExpiredRecords(t *testing.T) {
+	store := newInfluxDBPushStore(time.Minute)
+	store.ingest("cpu", "host=a", map[string]float64{"value": 1}, time.Now().Add(-time.Hour))
+	store.ingest("cpu", "host=b", map[string]float64{"value": 2}, time.Now())
+
+	values := store.values("cpu", "value")
+	if len(values) != 1 || values[0] != 2 {
+		t.Errorf("expected only the unexpired record, got %v", values)
+	}
+
+	if _, ok := store.series["cpu"]["host=a"]; ok {
+		t.Error("expected the expired record to be pruned")
+	}
+}
+
+func TestInfluxDBPushStoreValuesNoData(t *testing.T) {
+	store := newInfluxDBPushStore(time.Minute)
+	if values := store.values("cpu", "value"); len(values) != 0 {
+		t.Errorf("expected no values, got %v", values)
+	}
+}
+
+func TestAcquireInfluxDBPushListenerRejectsConflictingAuth(t *testing.T) {
+	addr := "127.0.0.1:0"
+
+	first := &influxDBPushMetadata{listenAddress: addr, path: influxDBPushDefaultPath, authToken: "token-a"}
+	l, err := acquireInfluxDBPushListener(first)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer releaseInfluxDBPushListener(first)
+
+	conflicting := &influxDBPushMetadata{listenAddress: addr, path: influxDBPushDefaultPath, authToken: "token-b"}
+	if _, err := acquireInfluxDBPushListener(conflicting); err == nil {
+		t.Error("expected an error for conflicting authToken on the same listenAddress/path")
+	}
+
+	matching := &influxDBPushMetadata{listenAddress: addr, path: influxDBPushDefaultPath, authToken: "token-a"}
+	l2, err := acquireInfluxDBPushListener(matching)
+	if err != nil {
+		t.Fatalf("unexpected error for matching auth config: %s", err)
+	}
+	defer releaseInfluxDBPushListener(matching)
+
+	if l != l2 {
+		t.Error("expected the matching registration to reuse the existing listener")
+	}
+}
@@ -3,11 +3,15 @@ package scalers
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	api "github.com/influxdata/influxdb-client-go/v2/api"
+	influxdb1 "github.com/influxdata/influxdb1-client/v2"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -19,22 +23,59 @@ import (
 
 type influxDBScaler struct {
 	client   influxdb2.Client
+	clientV1 influxdb1.Client
 	metadata *influxDBMetadata
 }
 
 type influxDBMetadata struct {
-	authToken        string
-	metricName       string
-	organizationName string
-	query            string
-	serverURL        string
-	unsafeSsl        bool
-	thresholdValue   float64
-	scalerIndex      int
+	version             int
+	authToken           string
+	metricName          string
+	organizationName    string
+	username            string
+	password            string
+	database            string
+	query               string
+	serverURL           string
+	unsafeSsl           bool
+	thresholdValue      float64
+	activationThreshold float64
+	aggregation         string
+	valueLocation       string
+	scalerIndex         int
 }
 
+const (
+	influxDBVersion1 = 1
+	influxDBVersion2 = 2
+)
+
+// supported aggregation modes for folding multiple records into one scaling signal
+const (
+	aggregationTypeSum   = "sum"
+	aggregationTypeAvg   = "avg"
+	aggregationTypeMin   = "min"
+	aggregationTypeMax   = "max"
+	aggregationTypeLast  = "last"
+	aggregationTypeCount = "count"
+)
+
 var influxDBLog = logf.Log.WithName("influxdb_scaler")
 
+// parseInfluxDBAggregation validates the aggregation metadata field, defaulting to "last" when unset
+func parseInfluxDBAggregation(val string) (string, error) {
+	aggregation := aggregationTypeLast
+	if val != "" {
+		aggregation = strings.ToLower(val)
+	}
+	switch aggregation {
+	case aggregationTypeSum, aggregationTypeAvg, aggregationTypeMin, aggregationTypeMax, aggregationTypeLast, aggregationTypeCount:
+		return aggregation, nil
+	default:
+		return "", fmt.Errorf("aggregation %s is not supported, must be one of sum, avg, min, max, last, count", aggregation)
+	}
+}
+
 // NewInfluxDBScaler creates a new influx db scaler
 func NewInfluxDBScaler(config *ScalerConfig) (Scaler, error) {
 	meta, err := parseInfluxDBMetadata(config)
@@ -43,6 +84,23 @@ func NewInfluxDBScaler(config *ScalerConfig) (Scaler, error) {
 	}
 
 	influxDBLog.Info("starting up influxdb client")
+
+	if meta.version == influxDBVersion1 {
+		clientV1, err := influxdb1.NewHTTPClient(influxdb1.HTTPConfig{
+			Addr:               meta.serverURL,
+			Username:           meta.username,
+			Password:           meta.password,
+			InsecureSkipVerify: meta.unsafeSsl,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating influxdb v1 client: %s", err)
+		}
+		return &influxDBScaler{
+			clientV1: clientV1,
+			metadata: meta,
+		}, nil
+	}
+
 	// In case unsafeSsl is enabled.
 	if meta.unsafeSsl {
 		return &influxDBScaler{
@@ -61,41 +119,95 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 	var authToken string
 	var metricName string
 	var organizationName string
+	var username string
+	var password string
+	var database string
 	var query string
 	var serverURL string
 	var unsafeSsl bool
 	var thresholdValue float64
+	var activationThreshold float64
+	var aggregation string
+	var valueLocation string
 
-	val, ok := config.TriggerMetadata["authToken"]
-	switch {
-	case ok && val != "":
-		authToken = val
-	case config.TriggerMetadata["authTokenFromEnv"] != "":
-		if val, ok := config.ResolvedEnv[config.TriggerMetadata["authTokenFromEnv"]]; ok {
-			authToken = val
+	version := influxDBVersion2
+	if val, ok := config.TriggerMetadata["version"]; ok {
+		parsedVal, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version: %s", err)
+		}
+		if parsedVal != influxDBVersion1 && parsedVal != influxDBVersion2 {
+			return nil, fmt.Errorf("version %d is not supported, must be 1 or 2", parsedVal)
+		}
+		version = parsedVal
+	}
+
+	if version == influxDBVersion1 {
+		val, ok := config.TriggerMetadata["username"]
+		switch {
+		case ok && val != "":
+			username = val
+		case config.TriggerMetadata["usernameFromEnv"] != "":
+			if val, ok := config.ResolvedEnv[config.TriggerMetadata["usernameFromEnv"]]; ok {
+				username = val
+			} else {
+				return nil, fmt.Errorf("no username given")
+			}
+		case config.AuthParams["username"] != "":
+			username = config.AuthParams["username"]
+		}
+
+		val, ok = config.TriggerMetadata["password"]
+		switch {
+		case ok && val != "":
+			password = val
+		case config.TriggerMetadata["passwordFromEnv"] != "":
+			if val, ok := config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]; ok {
+				password = val
+			} else {
+				return nil, fmt.Errorf("no password given")
+			}
+		case config.AuthParams["password"] != "":
+			password = config.AuthParams["password"]
+		}
+
+		if val, ok := config.TriggerMetadata["database"]; ok {
+			database = val
 		} else {
+			return nil, fmt.Errorf("no database given")
+		}
+	} else {
+		val, ok := config.TriggerMetadata["authToken"]
+		switch {
+		case ok && val != "":
+			authToken = val
+		case config.TriggerMetadata["authTokenFromEnv"] != "":
+			if val, ok := config.ResolvedEnv[config.TriggerMetadata["authTokenFromEnv"]]; ok {
+				authToken = val
+			} else {
+				return nil, fmt.Errorf("no auth token given")
+			}
+		case config.AuthParams["authToken"] != "":
+			authToken = config.AuthParams["authToken"]
+		default:
 			return nil, fmt.Errorf("no auth token given")
 		}
-	case config.AuthParams["authToken"] != "":
-		authToken = config.AuthParams["authToken"]
-	default:
-		return nil, fmt.Errorf("no auth token given")
-	}
 
-	val, ok = config.TriggerMetadata["organizationName"]
-	switch {
-	case ok && val != "":
-		organizationName = val
-	case config.TriggerMetadata["organizationNameFromEnv"] != "":
-		if val, ok := config.ResolvedEnv[config.TriggerMetadata["organizationNameFromEnv"]]; ok {
+		val, ok = config.TriggerMetadata["organizationName"]
+		switch {
+		case ok && val != "":
 			organizationName = val
-		} else {
+		case config.TriggerMetadata["organizationNameFromEnv"] != "":
+			if val, ok := config.ResolvedEnv[config.TriggerMetadata["organizationNameFromEnv"]]; ok {
+				organizationName = val
+			} else {
+				return nil, fmt.Errorf("no organization name given")
+			}
+		case config.AuthParams["organizationName"] != "":
+			organizationName = config.AuthParams["organizationName"]
+		default:
 			return nil, fmt.Errorf("no organization name given")
 		}
-	case config.AuthParams["organizationName"] != "":
-		organizationName = config.AuthParams["organizationName"]
-	default:
-		return nil, fmt.Errorf("no organization name given")
 	}
 
 	if val, ok := config.TriggerMetadata["query"]; ok {
@@ -114,6 +226,8 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 
 	if val, ok := config.TriggerMetadata["metricName"]; ok {
 		metricName = kedautil.NormalizeString(fmt.Sprintf("influxdb-%s", val))
+	} else if version == influxDBVersion1 {
+		metricName = kedautil.NormalizeString(fmt.Sprintf("influxdb-%s", database))
 	} else {
 		metricName = kedautil.NormalizeString(fmt.Sprintf("influxdb-%s", organizationName))
 	}
@@ -136,66 +250,220 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 		unsafeSsl = parsedVal
 	}
 
+	activationThreshold = 0
+	if val, ok := config.TriggerMetadata["activationThreshold"]; ok {
+		value, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationThreshold: failed to parse activationThreshold length %s", err.Error())
+		}
+		activationThreshold = value
+	}
+
+	aggregation, err := parseInfluxDBAggregation(config.TriggerMetadata["aggregation"])
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := config.TriggerMetadata["valueLocation"]; ok {
+		valueLocation = val
+	}
+
 	return &influxDBMetadata{
-		authToken:        authToken,
-		metricName:       metricName,
-		organizationName: organizationName,
-		query:            query,
-		serverURL:        serverURL,
-		thresholdValue:   thresholdValue,
-		unsafeSsl:        unsafeSsl,
-		scalerIndex:      config.ScalerIndex,
+		version:             version,
+		authToken:           authToken,
+		metricName:          metricName,
+		organizationName:    organizationName,
+		username:            username,
+		password:            password,
+		database:            database,
+		query:               query,
+		serverURL:           serverURL,
+		thresholdValue:      thresholdValue,
+		activationThreshold: activationThreshold,
+		aggregation:         aggregation,
+		valueLocation:       valueLocation,
+		unsafeSsl:           unsafeSsl,
+		scalerIndex:         config.ScalerIndex,
 	}, nil
 }
 
-// IsActive returns true if queried value is above the minimum value
+// IsActive returns true if queried value is above the activation threshold
 func (s *influxDBScaler) IsActive(ctx context.Context) (bool, error) {
-	queryAPI := s.client.QueryAPI(s.metadata.organizationName)
-
-	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query)
+	value, err := s.query(ctx)
 	if err != nil {
 		return false, err
 	}
 
-	return value > 0, nil
+	return value > s.metadata.activationThreshold, nil
+}
+
+// query runs the configured query against the influxdb instance, using the
+// v1 (InfluxQL) or v2 (Flux) API depending on the configured version
+func (s *influxDBScaler) query(ctx context.Context) (float64, error) {
+	if s.metadata.version == influxDBVersion1 {
+		return queryInfluxDBv1(s.clientV1, s.metadata.query, s.metadata.database, s.metadata.aggregation, s.metadata.valueLocation)
+	}
+
+	queryAPI := s.client.QueryAPI(s.metadata.organizationName)
+	return queryInfluxDB(ctx, queryAPI, s.metadata.query, s.metadata.aggregation, s.metadata.valueLocation)
 }
 
 // Close closes the connection of the client to the server
 func (s *influxDBScaler) Close(context.Context) error {
+	if s.metadata.version == influxDBVersion1 {
+		return s.clientV1.Close()
+	}
 	s.client.Close()
 	return nil
 }
 
-// queryInfluxDB runs the query against the associated influxdb database
-// there is an implicit assumption here that the first value returned from the iterator
-// will be the value of interest
-func queryInfluxDB(ctx context.Context, queryAPI api.QueryAPI, query string) (float64, error) {
+// queryInfluxDB runs the query against the associated influxdb database and aggregates every record returned
+func queryInfluxDB(ctx context.Context, queryAPI api.QueryAPI, query string, aggregation string, valueLocation string) (float64, error) {
 	result, err := queryAPI.Query(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	valueExists := result.Next()
-	if !valueExists {
+	var values []float64
+	for result.Next() {
+		var raw interface{}
+		if valueLocation != "" {
+			raw = result.Record().ValueByKey(valueLocation)
+		} else {
+			raw = result.Record().Value()
+		}
+
+		value, err := influxDBValueToFloat64(raw)
+		if err != nil {
+			return 0, err
+		}
+		values = append(values, value)
+	}
+	if err := result.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(values) == 0 {
 		return 0, fmt.Errorf("no results found from query")
 	}
 
-	switch valRaw := result.Record().Value().(type) {
+	return aggregateInfluxDBValues(values, aggregation), nil
+}
+
+// influxDBValueToFloat64 converts a query result value into a float64
+func influxDBValueToFloat64(valRaw interface{}) (float64, error) {
+	switch valRaw := valRaw.(type) {
 	case float64:
 		return valRaw, nil
 	case int64:
 		return float64(valRaw), nil
+	case uint64:
+		return float64(valRaw), nil
+	case json.Number:
+		return valRaw.Float64()
+	case time.Duration:
+		return float64(valRaw), nil
 	default:
 		return 0, fmt.Errorf("value of type %T could not be converted into a float", valRaw)
 	}
 }
 
+// aggregateInfluxDBValues folds the values returned by a query into a single scaling signal
+func aggregateInfluxDBValues(values []float64, aggregation string) float64 {
+	switch aggregation {
+	case aggregationTypeSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	case aggregationTypeAvg:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	case aggregationTypeMin:
+		min := values[0]
+		for _, v := range values[1:] {
+			if v < min {
+				min = v
+			}
+		}
+		return min
+	case aggregationTypeMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			if v > max {
+				max = v
+			}
+		}
+		return max
+	case aggregationTypeCount:
+		return float64(len(values))
+	case aggregationTypeLast:
+		fallthrough
+	default:
+		return values[len(values)-1]
+	}
+}
+
+// queryInfluxDBv1 runs the InfluxQL query against the associated influxdb v1 database and aggregates every row of every
+// series in the first statement's result; results of any further statements are not read
+func queryInfluxDBv1(clientV1 influxdb1.Client, query string, database string, aggregation string, valueLocation string) (float64, error) {
+	response, err := clientV1.Query(influxdb1.NewQuery(query, database, ""))
+	if err != nil {
+		return 0, err
+	}
+	if response.Error() != nil {
+		return 0, response.Error()
+	}
+
+	if len(response.Results) == 0 {
+		return 0, fmt.Errorf("no results found from query")
+	}
+
+	var values []float64
+	for _, series := range response.Results[0].Series {
+		columnIndex := len(series.Columns) - 1
+		if valueLocation != "" {
+			columnIndex = -1
+			for i, column := range series.Columns {
+				if column == valueLocation {
+					columnIndex = i
+					break
+				}
+			}
+			if columnIndex == -1 {
+				return 0, fmt.Errorf("valueLocation %s not found in query result columns", valueLocation)
+			}
+		}
+		if columnIndex < 0 {
+			return 0, fmt.Errorf("query result series has no columns")
+		}
+
+		for _, row := range series.Values {
+			if columnIndex >= len(row) {
+				return 0, fmt.Errorf("valueLocation column index %d out of range for query result", columnIndex)
+			}
+			value, err := influxDBValueToFloat64(row[columnIndex])
+			if err != nil {
+				return 0, err
+			}
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no results found from query")
+	}
+
+	return aggregateInfluxDBValues(values, aggregation), nil
+}
+
 // GetMetrics connects to influxdb via the client and returns a value based on the query
 func (s *influxDBScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	// Grab QueryAPI to make queries to influxdb instance
-	queryAPI := s.client.QueryAPI(s.metadata.organizationName)
-
-	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query)
+	value, err := s.query(ctx)
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, err
 	}
@@ -0,0 +1,172 @@
+package scalers
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	influxdb1 "github.com/influxdata/influxdb1-client/v2"
+)
+
+type fakeQueryAPI struct {
+	result *api.QueryTableResult
+}
+
+func (f *fakeQueryAPI) Query(context.Context, string) (*api.QueryTableResult, error) {
+	return f.result, nil
+}
+func (f *fakeQueryAPI) QueryWithParams(context.Context, string, interface{}) (*api.QueryTableResult, error) {
+	return f.result, nil
+}
+func (f *fakeQueryAPI) QueryRaw(context.Context, string, *domain.Dialect) (string, error) {
+	return "", nil
+}
+func (f *fakeQueryAPI) QueryRawWithParams(context.Context, string, *domain.Dialect, interface{}) (string, error) {
+	return "", nil
+}
+
+func TestQueryInfluxDBAggregatesEveryRecord(t *testing.T) {
+	csv := "" +
+		"#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string\n" +
+		"#group,false,false,true,true,false,false,true,true\n" +
+		"#default,_result,,,,,,,\n" +
+		",result,table,_start,_stop,_time,_value,_field,_measurement\n" +
+		",,0,2021-01-01T00:00:00Z,2021-01-01T01:00:00Z,2021-01-01T00:00:00Z,1.5,value,cpu\n" +
+		",,0,2021-01-01T00:00:00Z,2021-01-01T01:00:00Z,2021-01-01T00:01:00Z,2.5,value,cpu\n" +
+		"\n"
+	result := api.NewQueryTableResult(io.NopCloser(strings.NewReader(csv)))
+
+	value, err := queryInfluxDB(context.Background(), &fakeQueryAPI{result: result}, "", aggregationTypeSum, "_value")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value != 4 {
+		t.Errorf("expected 4, got %v", value)
+	}
+}
+
+type fakeInfluxDBv1Client struct {
+	response *influxdb1.Response
+}
+
+func (f *fakeInfluxDBv1Client) Ping(time.Duration) (time.Duration, string, error) { return 0, "", nil }
+func (f *fakeInfluxDBv1Client) Write(influxdb1.BatchPoints) error                 { return nil }
+func (f *fakeInfluxDBv1Client) Query(influxdb1.Query) (*influxdb1.Response, error) {
+	return f.response, nil
+}
+func (f *fakeInfluxDBv1Client) QueryAsChunk(influxdb1.Query) (*influxdb1.ChunkedResponse, error) {
+	return nil, nil
+}
+func (f *fakeInfluxDBv1Client) Close() error { return nil }
+
+func TestQueryInfluxDBv1AggregatesEverySeries(t *testing.T) {
+	response := &influxdb1.Response{
+		Results: []influxdb1.Result{
+			{
+				Series: []influxdb1.Row{
+					{
+						Columns: []string{"time", "value"},
+						Values: [][]interface{}{
+							{"2021-01-01T00:00:00Z", 1.0},
+						},
+					},
+					{
+						Columns: []string{"time", "value"},
+						Values: [][]interface{}{
+							{"2021-01-01T00:00:00Z", 2.0},
+							{"2021-01-01T00:01:00Z", 3.0},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	value, err := queryInfluxDBv1(&fakeInfluxDBv1Client{response: response}, "", "", aggregationTypeSum, "value")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value != 6 {
+		t.Errorf("expected 6, got %v", value)
+	}
+}
+
+func TestQueryInfluxDBv1NoResults(t *testing.T) {
+	response := &influxdb1.Response{Results: []influxdb1.Result{{}}}
+
+	_, err := queryInfluxDBv1(&fakeInfluxDBv1Client{response: response}, "", "", aggregationTypeLast, "")
+	if err == nil {
+		t.Error("expected an error for an empty result set")
+	}
+}
+
+func TestQueryInfluxDBv1NoColumns(t *testing.T) {
+	response := &influxdb1.Response{
+		Results: []influxdb1.Result{
+			{
+				Series: []influxdb1.Row{
+					{Values: [][]interface{}{{1.0}}},
+				},
+			},
+		},
+	}
+
+	_, err := queryInfluxDBv1(&fakeInfluxDBv1Client{response: response}, "", "", aggregationTypeLast, "")
+	if err == nil {
+		t.Error("expected an error for a series with no columns")
+	}
+}
+
+func TestAggregateInfluxDBValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		aggregation string
+		values      []float64
+		want        float64
+	}{
+		{"sum", aggregationTypeSum, []float64{1, 2, 3}, 6},
+		{"avg", aggregationTypeAvg, []float64{1, 2, 3}, 2},
+		{"min", aggregationTypeMin, []float64{3, 1, 2}, 1},
+		{"max", aggregationTypeMax, []float64{3, 1, 2}, 3},
+		{"count", aggregationTypeCount, []float64{3, 1, 2}, 3},
+		{"last", aggregationTypeLast, []float64{1, 2, 3}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aggregateInfluxDBValues(tt.values, tt.aggregation); got != tt.want {
+				t.Errorf("aggregateInfluxDBValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfluxDBValueToFloat64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    float64
+		wantErr bool
+	}{
+		{"float64", float64(1.5), 1.5, false},
+		{"int64", int64(2), 2, false},
+		{"uint64", uint64(3), 3, false},
+		{"string", "not a number", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := influxDBValueToFloat64(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("influxDBValueToFloat64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
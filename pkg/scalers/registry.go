@@ -0,0 +1,26 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+)
+
+// PushScaler is a Scaler driven by an external push source instead of being polled;
+// Run reports activity on active until ctx is done, closing it when it returns
+type PushScaler interface {
+	Scaler
+	Run(ctx context.Context, active chan<- bool)
+}
+
+// NewScaler builds the Scaler registered for triggerType. Only the influxdb and
+// influxdb-push trigger types are registered here; any other type returns an error.
+func NewScaler(triggerType string, config *ScalerConfig) (Scaler, error) {
+	switch triggerType {
+	case "influxdb":
+		return NewInfluxDBScaler(config)
+	case "influxdb-push":
+		return NewInfluxDBPushScaler(config)
+	default:
+		return nil, fmt.Errorf("no scaler found for type: %s", triggerType)
+	}
+}